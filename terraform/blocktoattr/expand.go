@@ -0,0 +1,188 @@
+package blocktoattr
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/terraform-linters/tflint-ruleset-terraform/terraform/configschema"
+)
+
+// ExpandAttr reads the attribute named attrName out of content, which must
+// have been decoded using the schema BodySchemaForAttr(attrName, itemSchema)
+// produced. If the configuration wrote attrName using attribute syntax, its
+// native *hclext.Attribute is returned unchanged. If it was written as one
+// or more nested blocks instead, a synthetic attribute is returned whose
+// expression is a tuple of object constructors, one per block instance,
+// with ranges pointing back at the original blocks. Returns nil if attrName
+// is absent entirely.
+func ExpandAttr(content *hclext.BodyContent, attrName string, itemSchema *configschema.Block) *hclext.Attribute {
+	if attr, exists := content.Attributes[attrName]; exists {
+		return attr
+	}
+
+	var blocks []*hclext.Block
+	for _, block := range content.Blocks {
+		if block.Type == attrName {
+			blocks = append(blocks, block)
+		}
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	exprs := make([]hcl.Expression, 0, len(blocks))
+	for _, block := range blocks {
+		exprs = append(exprs, objectExprFromBlock(block, itemSchema))
+	}
+
+	rng := blocks[0].DefRange
+	return &hclext.Attribute{
+		Name:      attrName,
+		Expr:      &tupleExpr{exprs: exprs, rng: rng},
+		Range:     rng,
+		NameRange: rng,
+	}
+}
+
+// expandBlockAttrs combines content's own native attributes with synthetic
+// attributes for any ambiguous name (per schema) that was instead written
+// as one or more nested blocks, recursing into each block's own content.
+func expandBlockAttrs(content *hclext.BodyContent, schema *configschema.Block) map[string]*hclext.Attribute {
+	attrs := make(map[string]*hclext.Attribute, len(content.Attributes))
+	for name, attr := range content.Attributes {
+		attrs[name] = attr
+	}
+
+	blocksByType := map[string][]*hclext.Block{}
+	for _, block := range content.Blocks {
+		blocksByType[block.Type] = append(blocksByType[block.Type], block)
+	}
+
+	for name, blocks := range blocksByType {
+		if _, exists := attrs[name]; exists {
+			continue
+		}
+		attrS, isAttr := schema.Attributes[name]
+		if !isAttr || !isAmbiguousType(attrS.Type) {
+			continue
+		}
+
+		nestedSchema := schema.BlockTypes[name]
+
+		exprs := make([]hcl.Expression, 0, len(blocks))
+		for _, block := range blocks {
+			var itemSchema *configschema.Block
+			if nestedSchema != nil {
+				itemSchema = &nestedSchema.Block
+			}
+			exprs = append(exprs, objectExprFromBlock(block, itemSchema))
+		}
+
+		rng := blocks[0].DefRange
+		attrs[name] = &hclext.Attribute{
+			Name:      name,
+			Expr:      &tupleExpr{exprs: exprs, rng: rng},
+			Range:     rng,
+			NameRange: rng,
+		}
+	}
+
+	return attrs
+}
+
+// objectExprFromBlock turns a single nested block into an object-constructor
+// expression equivalent to how it would have been written in attribute
+// syntax, recursing into the block's own content to resolve any further
+// block/attribute ambiguities described by itemSchema.
+func objectExprFromBlock(block *hclext.Block, itemSchema *configschema.Block) hcl.Expression {
+	rng := block.DefRange
+
+	var attrs map[string]*hclext.Attribute
+	if itemSchema != nil {
+		attrs = expandBlockAttrs(block.Body, itemSchema)
+	} else {
+		attrs = block.Body.Attributes
+	}
+
+	exprs := make(map[string]hcl.Expression, len(attrs))
+	for name, attr := range attrs {
+		exprs[name] = attr.Expr
+	}
+
+	return &objectExpr{exprs: exprs, rng: rng}
+}
+
+// tupleExpr and objectExpr are minimal hcl.Expression implementations used to
+// synthesize the tuple-of-objects expression ExpandAttr builds for a
+// block-syntax attribute. hclsyntax's own expression node types can't be used
+// here: their Exprs/ValueExpr fields are typed as the hclsyntax.Expression
+// interface, whose unexported walkChildNodes method a plain hcl.Expression
+// value (such as an *hclext.Attribute's Expr) doesn't implement.
+
+type tupleExpr struct {
+	exprs []hcl.Expression
+	rng   hcl.Range
+}
+
+func (e *tupleExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	vals := make([]cty.Value, len(e.exprs))
+	for i, expr := range e.exprs {
+		val, valDiags := expr.Value(ctx)
+		diags = append(diags, valDiags...)
+		vals[i] = val
+	}
+	if len(vals) == 0 {
+		return cty.EmptyTupleVal, diags
+	}
+	return cty.TupleVal(vals), diags
+}
+
+func (e *tupleExpr) Variables() []hcl.Traversal {
+	var traversals []hcl.Traversal
+	for _, expr := range e.exprs {
+		traversals = append(traversals, expr.Variables()...)
+	}
+	return traversals
+}
+
+func (e *tupleExpr) Range() hcl.Range {
+	return e.rng
+}
+
+func (e *tupleExpr) StartRange() hcl.Range {
+	return e.rng
+}
+
+type objectExpr struct {
+	exprs map[string]hcl.Expression
+	rng   hcl.Range
+}
+
+func (e *objectExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	vals := make(map[string]cty.Value, len(e.exprs))
+	for name, expr := range e.exprs {
+		val, valDiags := expr.Value(ctx)
+		diags = append(diags, valDiags...)
+		vals[name] = val
+	}
+	return cty.ObjectVal(vals), diags
+}
+
+func (e *objectExpr) Variables() []hcl.Traversal {
+	var traversals []hcl.Traversal
+	for _, expr := range e.exprs {
+		traversals = append(traversals, expr.Variables()...)
+	}
+	return traversals
+}
+
+func (e *objectExpr) Range() hcl.Range {
+	return e.rng
+}
+
+func (e *objectExpr) StartRange() hcl.Range {
+	return e.rng
+}
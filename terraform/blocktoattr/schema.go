@@ -0,0 +1,66 @@
+// Package blocktoattr implements the rewrite Terraform core performs (in its
+// own internal/configs/configschema "blocktoattr" support) so that an
+// attribute whose schema type is a list-of-object or set-of-object can be
+// written in configuration using either attribute syntax or repeated nested
+// blocks. Such a name is declared as an attribute in the schema; a BlockTypes
+// entry of the same name, if present, only describes each element's object
+// type. Before a caller can decode such a body uniformly it must fold any
+// blocks written for an ambiguous name into an equivalent attribute
+// expression.
+package blocktoattr
+
+import (
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/terraform-linters/tflint-ruleset-terraform/terraform/configschema"
+)
+
+func isAmbiguousType(ty cty.Type) bool {
+	if !ty.IsListType() && !ty.IsSetType() {
+		return false
+	}
+	return ty.ElementType().IsObjectType()
+}
+
+// BodySchemaForBlock builds the hclext.BodySchema needed to decode a body
+// matching schema, requesting every ambiguous attribute as both an
+// attribute and a nested block so that either syntax can be read back,
+// recursing into nested block types along the way.
+func BodySchemaForBlock(schema *configschema.Block) *hclext.BodySchema {
+	body := &hclext.BodySchema{}
+
+	for name, attrS := range schema.Attributes {
+		body.Attributes = append(body.Attributes, hclext.AttributeSchema{Name: name})
+
+		if isAmbiguousType(attrS.Type) {
+			var nestedBody *hclext.BodySchema
+			if nested, ok := schema.BlockTypes[name]; ok {
+				nestedBody = BodySchemaForBlock(&nested.Block)
+			}
+			body.Blocks = append(body.Blocks, hclext.BlockSchema{Type: name, Body: nestedBody})
+		}
+	}
+
+	for name, nested := range schema.BlockTypes {
+		if _, isAmbiguousAttr := schema.Attributes[name]; isAmbiguousAttr {
+			continue // requested as a block above only if the attribute's type is ambiguous
+		}
+		body.Blocks = append(body.Blocks, hclext.BlockSchema{Type: name, Body: BodySchemaForBlock(&nested.Block)})
+	}
+
+	return body
+}
+
+// BodySchemaForAttr builds the hclext.BodySchema needed to read a single
+// attribute named attrName out of a body, where itemSchema describes the
+// object type of each element so that blocks written for attrName (or for
+// any of its own nested ambiguous attributes) can be read back too.
+func BodySchemaForAttr(attrName string, itemSchema *configschema.Block) *hclext.BodySchema {
+	return &hclext.BodySchema{
+		Attributes: []hclext.AttributeSchema{{Name: attrName}},
+		Blocks: []hclext.BlockSchema{
+			{Type: attrName, Body: BodySchemaForBlock(itemSchema)},
+		},
+	}
+}
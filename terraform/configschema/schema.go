@@ -0,0 +1,44 @@
+// Package configschema provides a lightweight description of a resource (or
+// other block-based) schema, similar to the schema providers themselves
+// return over the plugin protocol. It exists so that runner helpers like
+// WalkExpandedResourceAttributes can reason about whether a given attribute
+// is ambiguous between attribute and nested-block syntax, without pulling in
+// Terraform's own internal packages.
+package configschema
+
+import "github.com/zclconf/go-cty/cty"
+
+// NestingMode describes the number of instances of a nested block that are
+// allowed, and how those instances combine into a single value.
+type NestingMode int
+
+const (
+	NestingSingle NestingMode = iota
+	NestingGroup
+	NestingList
+	NestingSet
+	NestingMap
+)
+
+// Block represents a schema for a configuration block, recursively, via
+// nested blocks.
+type Block struct {
+	Attributes map[string]*Attribute
+	BlockTypes map[string]*NestedBlock
+}
+
+// Attribute represents a schema for a single attribute within a Block.
+type Attribute struct {
+	Type     cty.Type
+	Optional bool
+	Required bool
+	Computed bool
+}
+
+// NestedBlock represents the schema for a nested block within a Block,
+// along with the nesting mode that governs how many instances of it are
+// permitted.
+type NestedBlock struct {
+	Block
+	Nesting NestingMode
+}
@@ -0,0 +1,52 @@
+package terraform
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+// Local represents a single entry declared inside a `locals` block.
+type Local struct {
+	Name     string
+	DefRange hcl.Range
+
+	// Attribute is the underlying attribute the local was declared with.
+	Attribute *hclext.Attribute
+}
+
+var localsSchema = &hclext.BodySchema{
+	Blocks: []hclext.BlockSchema{
+		{
+			Type: "locals",
+			Body: &hclext.BodySchema{
+				Mode: hclext.SchemaJustAttributesMode,
+			},
+		},
+	},
+}
+
+// GetLocals returns all locals declared in the module, keyed by name.
+//
+// Terraform allows `locals` blocks to be declared multiple times across
+// files, so entries here are merged; a later declaration of the same name
+// overwrites an earlier one, mirroring Terraform's own behavior.
+func (r *Runner) GetLocals() (map[string]*Local, hcl.Diagnostics) {
+	body, diags := r.getModuleContentDiags(localsSchema, &tflint.GetModuleContentOption{})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	locals := map[string]*Local{}
+	for _, block := range body.Blocks {
+		for name, attr := range block.Body.Attributes {
+			locals[name] = &Local{
+				Name:      name,
+				DefRange:  attr.Range,
+				Attribute: attr,
+			}
+		}
+	}
+
+	return locals, diags
+}
@@ -0,0 +1,191 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+// ModuleCall represents a `module` block.
+type ModuleCall struct {
+	Name     string
+	DefRange hcl.Range
+
+	Source         string
+	SourceAttr     *hclext.Attribute
+	ResolvedSource ModuleSource
+
+	Version     version.Constraints
+	VersionAttr *hclext.Attribute
+
+	CountExpr      hcl.Expression
+	CountRange     hcl.Range
+	ForEachExpr    hcl.Expression
+	ForEachRange   hcl.Range
+	DependsOn      hcl.Expression
+	DependsOnRange hcl.Range
+	Providers      hcl.Expression
+	ProvidersRange hcl.Range
+}
+
+var moduleCallSchema = &hclext.BodySchema{
+	Blocks: []hclext.BlockSchema{
+		{
+			Type:       "module",
+			LabelNames: []string{"name"},
+			Body: &hclext.BodySchema{
+				Mode: hclext.SchemaJustAttributesMode,
+			},
+		},
+	},
+}
+
+// moduleCallKnownArgs are the attributes GetModuleCalls itself recognizes;
+// everything else is either a variable assignment passed into the module or
+// one of moduleCallReservedArgNames.
+var moduleCallKnownArgs = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"count":      true,
+	"for_each":   true,
+	"depends_on": true,
+	"providers":  true,
+}
+
+// moduleCallReservedArgNames are names Terraform reserves for future use
+// inside `module` blocks. They can never be used as a variable assignment,
+// even though the child module might happen to declare a variable with that
+// name.
+var moduleCallReservedArgNames = map[string]bool{
+	"lifecycle": true,
+	"locals":    true,
+	"provider":  true,
+}
+
+// GetModuleCalls returns every `module` block declared in the module.
+func (r *Runner) GetModuleCalls() ([]*ModuleCall, hcl.Diagnostics) {
+	body, diags := r.getModuleContentDiags(moduleCallSchema, &tflint.GetModuleContentOption{})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	calls, callDiags := decodeModuleCalls(r, body)
+	diags = append(diags, callDiags...)
+	return calls, diags
+}
+
+// decodeModuleCalls decodes every `module` block out of body. body need only
+// have been decoded against a schema that puts "module" blocks in
+// SchemaJustAttributesMode; other block types it may contain are ignored.
+func decodeModuleCalls(r *Runner, body *hclext.BodyContent) ([]*ModuleCall, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	var calls []*ModuleCall
+	for _, block := range body.Blocks {
+		if block.Type != "module" {
+			continue
+		}
+		call := &ModuleCall{
+			Name:     block.Labels[0],
+			DefRange: block.DefRange,
+		}
+
+		if attr, exists := block.Body.Attributes["source"]; exists {
+			var source string
+			if err := r.EvaluateExpr(attr.Expr, &source, nil); err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid source argument",
+					Detail:   err.Error(),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+			call.Source = source
+			call.SourceAttr = attr
+			call.ResolvedSource = parseModuleSource(source)
+		}
+
+		if attr, exists := block.Body.Attributes["version"]; exists {
+			var versionStr string
+			if err := r.EvaluateExpr(attr.Expr, &versionStr, nil); err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid version argument",
+					Detail:   err.Error(),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+
+			constraints, err := version.NewConstraint(versionStr)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid version constraint",
+					Detail:   err.Error(),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+			call.Version = constraints
+			call.VersionAttr = attr
+		}
+
+		if attr, exists := block.Body.Attributes["count"]; exists {
+			call.CountExpr = attr.Expr
+			call.CountRange = attr.Range
+		}
+		if attr, exists := block.Body.Attributes["for_each"]; exists {
+			call.ForEachExpr = attr.Expr
+			call.ForEachRange = attr.Range
+		}
+		if attr, exists := block.Body.Attributes["depends_on"]; exists {
+			call.DependsOn = attr.Expr
+			call.DependsOnRange = attr.Range
+		}
+		if attr, exists := block.Body.Attributes["providers"]; exists {
+			call.Providers = attr.Expr
+			call.ProvidersRange = attr.Range
+		}
+
+		diags = append(diags, validateModuleSource(call)...)
+
+		calls = append(calls, call)
+	}
+
+	return calls, diags
+}
+
+// GetModuleCallReservedArgs returns a diagnostic for every attribute inside a
+// `module` block whose name Terraform reserves for future use (such as
+// `lifecycle`, `locals`, or the singular `provider`), mirroring the
+// reserved-argument checks Terraform core itself performs when loading
+// module calls.
+func (r *Runner) GetModuleCallReservedArgs() hcl.Diagnostics {
+	body, diags := r.getModuleContentDiags(moduleCallSchema, &tflint.GetModuleContentOption{})
+	if diags.HasErrors() {
+		return diags
+	}
+
+	for _, block := range body.Blocks {
+		for name, attr := range block.Body.Attributes {
+			if moduleCallKnownArgs[name] {
+				continue
+			}
+			if moduleCallReservedArgNames[name] {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Reserved argument name in module block",
+					Detail:   fmt.Sprintf("the name %q is reserved for future versions of Terraform and cannot be used as an argument in module %q", name, block.Labels[0]),
+					Subject:  attr.NameRange.Ptr(),
+				})
+			}
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,219 @@
+package terraform
+
+import (
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+// Variable represents a `variable` block declaration.
+type Variable struct {
+	Name     string
+	DefRange hcl.Range
+}
+
+// Output represents an `output` block declaration.
+type Output struct {
+	Name     string
+	DefRange hcl.Range
+}
+
+// ModuleMeta aggregates everything a rule is likely to want to know about a
+// module as a whole, so that rules needing cross-file context don't each
+// have to compose GetModuleCalls/GetLocals/GetProviderRefs/etc. themselves.
+type ModuleMeta struct {
+	// CoreVersionConstraints is the merged set of `required_version`
+	// constraints declared across every `terraform` block in the module.
+	CoreVersionConstraints version.Constraints
+
+	ProviderRequirements map[string]*ProviderRequirement
+	Variables            map[string]*Variable
+	Outputs              map[string]*Output
+	ModuleCalls          []*ModuleCall
+
+	// Files is the sorted list of filenames that declare at least one block
+	// of a type listed in moduleMetaSchema. A file holding only other block
+	// types (such as moved or import), or no blocks at all, is not listed.
+	Files []string
+
+	// PrimaryFile is "main.tf" when present, or the lexicographically first
+	// file otherwise. Rules reporting a module-wide problem with no more
+	// specific location should anchor their diagnostic here.
+	PrimaryFile string
+}
+
+// moduleMetaSchema is a superset of terraformBlockForProviderRequirementsSchema
+// and moduleCallSchema, so that GetModuleMeta can compute
+// ProviderRequirements and ModuleCalls from a single module walk instead of
+// delegating to GetProviderRequirements/GetModuleCalls for their own walks.
+// Its shape must stay compatible with decodeProviderRequirements and
+// decodeModuleCalls, which are shared with GetProviderRequirements and
+// GetModuleCalls respectively. It is not a superset of localsSchema or
+// providerRefSchema: the "locals", "resource", "data" and "check" block
+// entries here exist only to collect filenames into ModuleMeta.Files and
+// lack the attribute bodies GetLocals/GetProviderRefs need, so they cannot
+// be reused to decode those values.
+var moduleMetaSchema = &hclext.BodySchema{
+	Blocks: []hclext.BlockSchema{
+		{
+			Type: "terraform",
+			Body: &hclext.BodySchema{
+				Attributes: []hclext.AttributeSchema{{Name: "required_version"}},
+				Blocks: []hclext.BlockSchema{
+					{
+						Type: "required_providers",
+						Body: &hclext.BodySchema{
+							Mode: hclext.SchemaJustAttributesMode,
+						},
+					},
+				},
+			},
+		},
+		{
+			Type:       "variable",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type:       "output",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type: "locals",
+		},
+		{
+			Type:       "module",
+			LabelNames: []string{"name"},
+			Body: &hclext.BodySchema{
+				Mode: hclext.SchemaJustAttributesMode,
+			},
+		},
+		{
+			Type:       "resource",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type:       "data",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type:       "provider",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type:       "check",
+			LabelNames: []string{"name"},
+			Body: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{Type: "data", LabelNames: []string{"type", "name"}},
+				},
+			},
+		},
+	},
+}
+
+// GetModuleMeta returns the module's aggregated metadata, computing and
+// caching it on the first call. It walks the module's files exactly once,
+// sharing its decoding with GetProviderRequirements and GetModuleCalls
+// rather than calling them.
+func (r *Runner) GetModuleMeta() (*ModuleMeta, hcl.Diagnostics) {
+	if r.moduleMeta != nil {
+		return r.moduleMeta, nil
+	}
+
+	var diags hcl.Diagnostics
+
+	body, bodyDiags := r.getModuleContentDiags(moduleMetaSchema, &tflint.GetModuleContentOption{})
+	diags = append(diags, bodyDiags...)
+	if bodyDiags.HasErrors() {
+		return nil, diags
+	}
+
+	meta := &ModuleMeta{
+		Variables: map[string]*Variable{},
+		Outputs:   map[string]*Output{},
+	}
+
+	files := map[string]bool{}
+	for _, block := range body.Blocks {
+		files[block.DefRange.Filename] = true
+		for _, nested := range block.Body.Blocks {
+			files[nested.DefRange.Filename] = true
+		}
+
+		switch block.Type {
+		case "terraform":
+			attr, exists := block.Body.Attributes["required_version"]
+			if !exists {
+				continue
+			}
+			var constraintStr string
+			if err := r.EvaluateExpr(attr.Expr, &constraintStr, nil); err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid required_version argument",
+					Detail:   err.Error(),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+			constraints, err := version.NewConstraint(constraintStr)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid version constraint",
+					Detail:   err.Error(),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+			meta.CoreVersionConstraints = append(meta.CoreVersionConstraints, constraints...)
+
+		case "variable":
+			name := block.Labels[0]
+			meta.Variables[name] = &Variable{Name: name, DefRange: block.DefRange}
+
+		case "output":
+			name := block.Labels[0]
+			meta.Outputs[name] = &Output{Name: name, DefRange: block.DefRange}
+		}
+	}
+
+	reqs, reqDiags := decodeProviderRequirements(body)
+	diags = append(diags, reqDiags...)
+	meta.ProviderRequirements = reqs
+
+	calls, callDiags := decodeModuleCalls(r, body)
+	diags = append(diags, callDiags...)
+	meta.ModuleCalls = calls
+
+	for filename := range files {
+		meta.Files = append(meta.Files, filename)
+	}
+	sort.Strings(meta.Files)
+
+	meta.PrimaryFile = primaryFile(meta.Files)
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	r.moduleMeta = meta
+	return meta, diags
+}
+
+// primaryFile picks "main.tf" when present, falling back to the
+// lexicographically first file.
+func primaryFile(files []string) string {
+	for _, f := range files {
+		if f == "main.tf" {
+			return f
+		}
+	}
+	if len(files) > 0 {
+		return files[0]
+	}
+	return ""
+}
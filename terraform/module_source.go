@@ -0,0 +1,248 @@
+package terraform
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ModuleSource classifies the address a `module` block's `source` argument
+// resolves to. It is implemented by ModuleSourceLocal, ModuleSourceRegistry,
+// and ModuleSourceRemote.
+type ModuleSource interface {
+	moduleSource()
+}
+
+// ModuleSourceLocal is a source that resolves relative to the calling
+// module's own directory, such as "./server" or "../shared/vpc".
+type ModuleSourceLocal struct {
+	// Path is the source string as written, unmodified.
+	Path string
+}
+
+func (ModuleSourceLocal) moduleSource() {}
+
+// ModuleSourceRegistry is a source that resolves to a module published in a
+// Terraform module registry, such as "hashicorp/consul/aws" or
+// "app.terraform.io/example-corp/vpc/aws//modules/subnets".
+type ModuleSourceRegistry struct {
+	Host         string
+	Namespace    string
+	Name         string
+	TargetSystem string
+
+	// Subdir is the portion of the source after a "//" separator, if any.
+	Subdir string
+}
+
+func (ModuleSourceRegistry) moduleSource() {}
+
+// ModuleSourceRemote is a source resolved by a go-getter-style detector,
+// such as a git, Mercurial, S3, GCS, or plain HTTP(S) URL.
+type ModuleSourceRemote struct {
+	// Detector is the name of the go-getter detector that matched this
+	// source, e.g. "git", "github", "s3", "http".
+	Detector string
+
+	// Raw is the source string as written, with any forced-detector prefix
+	// and subdir suffix stripped.
+	Raw string
+
+	// Subdir is the portion of the source after a "//" separator, if any.
+	Subdir string
+}
+
+func (ModuleSourceRemote) moduleSource() {}
+
+// registrySourceRe matches a Terraform registry source address, with an
+// optional hostname followed by exactly a namespace, name, and target
+// system, e.g. "hashicorp/consul/aws" or "registry.example.com/org/name/aws".
+var registrySourceRe = regexp.MustCompile(
+	`^(?:([0-9A-Za-z_.-]+\.[0-9A-Za-z_.-]+(?::\d+)?)/)?([0-9A-Za-z_-]+)/([0-9A-Za-z_-]+)/([0-9A-Za-z_-]+)$`,
+)
+
+// knownRemoteHostPrefixes maps a source's hostname-ish prefix to the
+// go-getter detector that would claim it.
+var knownRemoteHostPrefixes = map[string]string{
+	"github.com/":    "github",
+	"bitbucket.org/": "bitbucket",
+}
+
+// parseModuleSource classifies a module call's source argument, splitting
+// off any "//subdir" suffix along the way.
+func parseModuleSource(source string) ModuleSource {
+	if source == "" {
+		return ModuleSourceLocal{Path: source}
+	}
+
+	if source == "." || source == ".." || strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") ||
+		strings.HasPrefix(source, ".\\") || strings.HasPrefix(source, "..\\") {
+		return ModuleSourceLocal{Path: source}
+	}
+
+	raw, subdir := splitSubdir(source)
+
+	if detector, rest := forcedDetector(raw); detector != "" {
+		return ModuleSourceRemote{Detector: detector, Raw: rest, Subdir: subdir}
+	}
+
+	if m := registrySourceRe.FindStringSubmatch(raw); m != nil {
+		host := m[1]
+		if host == "" {
+			host = defaultProviderRegistryHost
+		}
+		return ModuleSourceRegistry{
+			Host:         host,
+			Namespace:    m[2],
+			Name:         m[3],
+			TargetSystem: m[4],
+			Subdir:       subdir,
+		}
+	}
+
+	for prefix, detector := range knownRemoteHostPrefixes {
+		if strings.HasPrefix(raw, prefix) {
+			return ModuleSourceRemote{Detector: detector, Raw: raw, Subdir: subdir}
+		}
+	}
+
+	if u, err := url.Parse(raw); err == nil && u.Scheme != "" {
+		return ModuleSourceRemote{Detector: u.Scheme, Raw: raw, Subdir: subdir}
+	}
+
+	return ModuleSourceRemote{Detector: "generic", Raw: raw, Subdir: subdir}
+}
+
+// forcedDetector recognizes a go-getter "forced" detector prefix such as
+// "git::" or "s3::", returning the detector name and the remaining source.
+func forcedDetector(source string) (detector, rest string) {
+	if idx := strings.Index(source, "::"); idx > 0 {
+		return source[:idx], source[idx+2:]
+	}
+	return "", source
+}
+
+// splitSubdir separates a go-getter style "//subdir" suffix from source,
+// taking care not to confuse it with the "//" in a URL scheme like
+// "https://".
+func splitSubdir(source string) (raw, subdir string) {
+	schemeEnd := strings.Index(source, "://")
+	searchFrom := 0
+	if schemeEnd >= 0 {
+		searchFrom = schemeEnd + len("://")
+	}
+
+	idx := strings.Index(source[searchFrom:], "//")
+	if idx < 0 {
+		return source, ""
+	}
+	idx += searchFrom
+	return source[:idx], source[idx+2:]
+}
+
+// maxLocalSourceParentLevels bounds how many directories a local source is
+// allowed to climb via "../" before it's considered to have escaped the
+// repository root. The runner has no notion of where the repository root
+// actually is, so this is a heuristic rather than a precise check.
+const maxLocalSourceParentLevels = 2
+
+// localSourceEscapesRoot reports whether a local module source path climbs
+// more parent directories than maxLocalSourceParentLevels allows.
+func localSourceEscapesRoot(localPath string) bool {
+	clean := path.Clean(localPath)
+
+	levels := 0
+	for _, seg := range strings.Split(clean, "/") {
+		if seg != ".." {
+			break
+		}
+		levels++
+	}
+	return levels > maxLocalSourceParentLevels
+}
+
+// validateModuleSource reports the class of source-related problems
+// GetModuleCalls can catch up front, so that individual rules don't each
+// need to re-parse call.Source: version constraints on a source that
+// doesn't support them, a git "?ref=" used alongside a version constraint,
+// and local sources that climb out of the repository.
+func validateModuleSource(call *ModuleCall) hcl.Diagnostics {
+	if call.ResolvedSource == nil {
+		return nil
+	}
+
+	var diags hcl.Diagnostics
+
+	switch src := call.ResolvedSource.(type) {
+	case ModuleSourceLocal:
+		if call.VersionAttr != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid version constraint",
+				Detail:   "a version constraint is only supported for modules hosted in a module registry; local sources are always used as they are found on disk",
+				Subject:  call.VersionAttr.Range.Ptr(),
+			})
+		}
+		if localSourceEscapesRoot(src.Path) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Local module source escapes the repository",
+				Detail:   `this module's source climbs too many parent directories and may resolve outside of the repository root`,
+				Subject:  call.SourceAttr.Range.Ptr(),
+			})
+		}
+
+	case ModuleSourceRemote:
+		if call.VersionAttr != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid version constraint",
+				Detail:   "a version constraint is only supported for modules hosted in a module registry; use a ref/tag in the source URL to pin a remote module instead",
+				Subject:  call.VersionAttr.Range.Ptr(),
+			})
+		}
+		if hasGitRef(src) && call.VersionAttr != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Conflicting module source pin",
+				Detail:   "this module's source already pins a ref via \"?ref=\" and also sets a version constraint; only one of the two should be used",
+				Subject:  call.SourceAttr.Range.Ptr(),
+			})
+		}
+
+	case ModuleSourceRegistry:
+		if call.VersionAttr != nil && hasBuildMetadata(call.Version.String()) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid version constraint for registry module",
+				Detail:   "version constraints with build metadata (a \"+\" suffix) can never match a version published to a module registry",
+				Subject:  call.VersionAttr.Range.Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+// hasGitRef reports whether a remote module source pins a ref via the
+// go-getter "?ref=" query parameter, which is only meaningful for git (and
+// git-backed) sources.
+func hasGitRef(src ModuleSourceRemote) bool {
+	if src.Detector != "git" && src.Detector != "github" {
+		return false
+	}
+	if idx := strings.Index(src.Raw, "?"); idx >= 0 {
+		return strings.Contains(src.Raw[idx+1:], "ref=")
+	}
+	return false
+}
+
+// hasBuildMetadata reports whether a version constraint string carries
+// SemVer build metadata (a "+" suffix), which a module registry never
+// publishes and so can never satisfy.
+func hasBuildMetadata(constraint string) bool {
+	return strings.Contains(constraint, "+")
+}
@@ -0,0 +1,259 @@
+package terraform
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+// ProviderRef represents a reference to a provider, either declared
+// explicitly (a `provider` block, a `provider` meta-argument, or a module's
+// `providers` map) or inferred from a resource/data source type name.
+type ProviderRef struct {
+	Name     string
+	DefRange hcl.Range
+}
+
+var providerRefSchema = &hclext.BodySchema{
+	Blocks: []hclext.BlockSchema{
+		{
+			Type:       "resource",
+			LabelNames: []string{"type", "name"},
+			Body: &hclext.BodySchema{
+				Attributes: []hclext.AttributeSchema{{Name: "provider"}},
+			},
+		},
+		{
+			Type:       "data",
+			LabelNames: []string{"type", "name"},
+			Body: &hclext.BodySchema{
+				Attributes: []hclext.AttributeSchema{{Name: "provider"}},
+			},
+		},
+		{
+			Type:       "provider",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type:       "module",
+			LabelNames: []string{"name"},
+			Body: &hclext.BodySchema{
+				Attributes: []hclext.AttributeSchema{{Name: "providers"}},
+			},
+		},
+		{
+			Type:       "check",
+			LabelNames: []string{"name"},
+			Body: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{
+						Type:       "data",
+						LabelNames: []string{"type", "name"},
+						Body: &hclext.BodySchema{
+							Attributes: []hclext.AttributeSchema{{Name: "provider"}},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+// GetProviderRefs returns every provider that the module refers to, keyed by
+// local name. This includes providers inferred from resource/data type
+// prefixes, explicit `provider` meta-arguments, `provider` blocks, module
+// `providers` maps, and provider-defined functions (`provider::name::fn`).
+func (r *Runner) GetProviderRefs() (map[string]*ProviderRef, hcl.Diagnostics) {
+	body, diags := r.getModuleContentDiags(providerRefSchema, &tflint.GetModuleContentOption{})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	refs := map[string]*ProviderRef{}
+	addRef := func(name string, rng hcl.Range) {
+		if _, exists := refs[name]; !exists {
+			refs[name] = &ProviderRef{Name: name, DefRange: rng}
+		}
+	}
+
+	var walkDataBlock func(block *hclext.Block)
+	walkDataBlock = func(block *hclext.Block) {
+		if attr, exists := block.Body.Attributes["provider"]; exists {
+			if name := providerNameFromExpr(attr.Expr); name != "" {
+				addRef(name, block.DefRange)
+				return
+			}
+		}
+		addRef(providerNameFromType(block.Labels[0]), block.DefRange)
+	}
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "resource", "data":
+			walkDataBlock(block)
+		case "provider":
+			addRef(block.Labels[0], block.DefRange)
+		case "module":
+			if attr, exists := block.Body.Attributes["providers"]; exists {
+				for _, name := range providerNamesFromProvidersMap(attr.Expr) {
+					addRef(name, block.DefRange)
+				}
+			}
+		case "check":
+			for _, dataBlock := range block.Body.Blocks {
+				walkDataBlock(dataBlock)
+			}
+		}
+	}
+
+	for _, name := range providerDefinedFunctionRefs(r) {
+		addRef(name.name, name.rng)
+	}
+
+	return refs, diags
+}
+
+// providerNameFromType extracts the provider local name from a resource type
+// name, e.g. "google_compute_instance" -> "google".
+func providerNameFromType(resourceType string) string {
+	if idx := strings.Index(resourceType, "_"); idx > 0 {
+		return resourceType[:idx]
+	}
+	return resourceType
+}
+
+// providerNameFromExpr extracts the provider name out of a `provider = foo.bar`
+// meta-argument expression.
+func providerNameFromExpr(expr hcl.Expression) string {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() || len(traversal) == 0 {
+		return ""
+	}
+	if root, ok := traversal[0].(hcl.TraverseRoot); ok {
+		return root.Name
+	}
+	return ""
+}
+
+type providerRefLoc struct {
+	name string
+	rng  hcl.Range
+}
+
+// providerNamesFromProvidersMap extracts provider names from the values of a
+// module's `providers = { ... }` map. The module's own `providers` meta-argument
+// doesn't introduce a new provider configuration, so callers should attribute
+// the reference to the enclosing module block rather than to the individual
+// map entry.
+func providerNamesFromProvidersMap(expr hcl.Expression) []string {
+	var names []string
+
+	pairs, diags := hcl.ExprMap(expr)
+	if diags.HasErrors() {
+		return names
+	}
+	for _, pair := range pairs {
+		if name := providerNameFromExpr(pair.Value); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// providerDefinedFunctionRefs scans every expression in the module for calls
+// to provider-defined functions (`provider::name::function(...)`), which
+// implicitly reference the named provider.
+func providerDefinedFunctionRefs(r *Runner) []providerRefLoc {
+	var refs []providerRefLoc
+
+	addCall := func(name string, rng hcl.Range) {
+		parts := strings.SplitN(name, "::", 3)
+		if len(parts) == 3 && parts[0] == "provider" {
+			refs = append(refs, providerRefLoc{name: parts[1], rng: rng})
+		}
+	}
+
+	diags := r.WalkExpressions(tflint.ExprWalkFunc(func(expr hcl.Expression) hcl.Diagnostics {
+		if node, ok := expr.(hclsyntax.Expression); ok {
+			hclsyntax.VisitAll(node, func(n hclsyntax.Node) hcl.Diagnostics {
+				if call, ok := n.(*hclsyntax.FunctionCallExpr); ok {
+					addCall(call.Name, call.Range())
+				}
+				return nil
+			})
+			return nil
+		}
+
+		// JSON syntax doesn't give us hclsyntax nodes to walk directly: each
+		// top-level expression wraps a whole (possibly deeply nested) JSON
+		// value, with any computed content living inside "${...}" template
+		// strings. Recurse through the JSON structure and re-parse any
+		// string leaf as a template to find function calls within it.
+		refs = append(refs, providerDefinedFunctionRefsInJSON(r, expr)...)
+		return nil
+	}))
+	_ = diags
+
+	return refs
+}
+
+// providerDefinedFunctionRefsInJSON recurses through a JSON-syntax
+// expression's object/array structure looking for "${...}" template strings,
+// re-parsing each one to find provider-defined function calls within it.
+func providerDefinedFunctionRefsInJSON(r *Runner, expr hcl.Expression) []providerRefLoc {
+	var refs []providerRefLoc
+
+	if pairs, diags := hcl.ExprMap(expr); !diags.HasErrors() {
+		for _, pair := range pairs {
+			refs = append(refs, providerDefinedFunctionRefsInJSON(r, pair.Key)...)
+			refs = append(refs, providerDefinedFunctionRefsInJSON(r, pair.Value)...)
+		}
+		return refs
+	}
+	if items, diags := hcl.ExprList(expr); !diags.HasErrors() {
+		for _, item := range items {
+			refs = append(refs, providerDefinedFunctionRefsInJSON(r, item)...)
+		}
+		return refs
+	}
+
+	rng := expr.Range()
+	file, err := r.GetFile(rng.Filename)
+	if err != nil || file == nil || rng.Start.Byte < 0 || rng.End.Byte > len(file.Bytes) {
+		return refs
+	}
+
+	// expr.Range() covers the raw JSON string token, quotes and escapes
+	// included; unmarshal it to recover the unescaped template source, the
+	// same text hclsyntax would have seen in native syntax.
+	var src string
+	if err := json.Unmarshal(file.Bytes[rng.Start.Byte:rng.End.Byte], &src); err != nil {
+		return refs
+	}
+
+	tmpl, diags := hclsyntax.ParseTemplate([]byte(src), rng.Filename, hcl.Pos{
+		Line: rng.Start.Line,
+		// Skip over the opening quote mark, mirroring how HCL's own JSON
+		// expression type re-parses string values as native syntax.
+		Byte:   rng.Start.Byte + 1,
+		Column: rng.Start.Column + 1,
+	})
+	if diags.HasErrors() {
+		return refs
+	}
+
+	hclsyntax.VisitAll(tmpl, func(n hclsyntax.Node) hcl.Diagnostics {
+		if call, ok := n.(*hclsyntax.FunctionCallExpr); ok {
+			if parts := strings.SplitN(call.Name, "::", 3); len(parts) == 3 && parts[0] == "provider" {
+				refs = append(refs, providerRefLoc{name: parts[1], rng: call.Range()})
+			}
+		}
+		return nil
+	})
+
+	return refs
+}
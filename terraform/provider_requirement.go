@@ -0,0 +1,275 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// defaultProviderRegistryHost is used to qualify provider source addresses
+// that omit a hostname, matching Terraform's own default provider registry.
+const defaultProviderRegistryHost = "registry.terraform.io"
+
+// reservedProviderLocalNames are local names Terraform itself reserves and
+// refuses to let a module declare inside `required_providers`.
+var reservedProviderLocalNames = map[string]bool{
+	"terraform": true,
+}
+
+// ProviderRequirement represents a single entry of a `required_providers`
+// block, merged across every `terraform` block in the module.
+type ProviderRequirement struct {
+	LocalName string
+	DefRange  hcl.Range
+
+	Hostname  string
+	Namespace string
+	Type      string
+
+	Constraints     version.Constraints
+	ConstraintsExpr hcl.Expression
+
+	ConfigurationAliases []hcl.Traversal
+}
+
+var terraformBlockForProviderRequirementsSchema = &hclext.BodySchema{
+	Blocks: []hclext.BlockSchema{
+		{
+			Type: "terraform",
+			Body: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{
+						Type: "required_providers",
+						Body: &hclext.BodySchema{
+							Mode: hclext.SchemaJustAttributesMode,
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+// GetProviderRequirements walks every `terraform` block in the module and
+// decodes its `required_providers` entries, merging the results across
+// files and blocks.
+//
+// Declaring the same local name more than once, or reusing a name Terraform
+// reserves (such as "terraform"), is reported as a diagnostic rather than
+// silently overwriting the earlier entry.
+func (r *Runner) GetProviderRequirements() (map[string]*ProviderRequirement, hcl.Diagnostics) {
+	body, diags := r.getModuleContentDiags(terraformBlockForProviderRequirementsSchema, &tflint.GetModuleContentOption{})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	reqs, reqDiags := decodeProviderRequirements(body)
+	diags = append(diags, reqDiags...)
+	return reqs, diags
+}
+
+// decodeProviderRequirements decodes the `required_providers` entries out of
+// body's `terraform` blocks, merging the results across blocks. body need
+// only have been decoded against a schema that nests a `required_providers`
+// block (in SchemaJustAttributesMode) under "terraform"; other block types
+// it may contain are ignored.
+func decodeProviderRequirements(body *hclext.BodyContent) (map[string]*ProviderRequirement, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	reqs := map[string]*ProviderRequirement{}
+
+	for _, tfBlock := range body.Blocks {
+		if tfBlock.Type != "terraform" {
+			continue
+		}
+		for _, rpBlock := range tfBlock.Body.Blocks {
+			for name, attr := range rpBlock.Body.Attributes {
+				if reservedProviderLocalNames[name] {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid provider local name",
+						Detail:   fmt.Sprintf("%q is a reserved provider local name and cannot be used in required_providers", name),
+						Subject:  attr.NameRange.Ptr(),
+					})
+					continue
+				}
+
+				if existing, exists := reqs[name]; exists {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Duplicate required provider",
+						Detail:   fmt.Sprintf("provider %q was already required at %s", name, existing.DefRange),
+						Subject:  attr.NameRange.Ptr(),
+					})
+					continue
+				}
+
+				req, reqDiags := decodeProviderRequirement(name, attr)
+				diags = append(diags, reqDiags...)
+				if req != nil {
+					reqs[name] = req
+				}
+			}
+		}
+	}
+
+	return reqs, diags
+}
+
+// decodeProviderRequirement decodes a single `required_providers` entry,
+// accepting both the object form (`aws = { source = "...", version = "..." }`)
+// and the legacy string-shorthand form (`aws = "~> 5.0"`).
+func decodeProviderRequirement(name string, attr *hclext.Attribute) (*ProviderRequirement, hcl.Diagnostics) {
+	req := &ProviderRequirement{
+		LocalName: name,
+		DefRange:  attr.Range,
+	}
+
+	pairs, mapDiags := hcl.ExprMap(attr.Expr)
+	if mapDiags.HasErrors() {
+		// Legacy shorthand: the whole expression is a version constraint string.
+		var constraintStr string
+		if err := evaluateStringExpr(attr.Expr, &constraintStr); err != nil {
+			return nil, hcl.Diagnostics{{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid required_providers entry",
+				Detail:   err.Error(),
+				Subject:  attr.Expr.Range().Ptr(),
+			}}
+		}
+
+		constraints, err := version.NewConstraint(constraintStr)
+		if err != nil {
+			return nil, hcl.Diagnostics{{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid version constraint",
+				Detail:   err.Error(),
+				Subject:  attr.Expr.Range().Ptr(),
+			}}
+		}
+
+		req.Hostname, req.Namespace, req.Type = defaultProviderRegistryHost, "hashicorp", name
+		req.Constraints = constraints
+		req.ConstraintsExpr = attr.Expr
+		return req, nil
+	}
+
+	var diags hcl.Diagnostics
+	req.Hostname, req.Namespace, req.Type = defaultProviderRegistryHost, "hashicorp", name
+
+	for _, pair := range pairs {
+		key, keyDiags := pairKeyString(pair.Key)
+		if keyDiags.HasErrors() {
+			diags = append(diags, keyDiags...)
+			continue
+		}
+
+		switch key {
+		case "source":
+			var source string
+			if err := evaluateStringExpr(pair.Value, &source); err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid source argument",
+					Detail:   err.Error(),
+					Subject:  pair.Value.Range().Ptr(),
+				})
+				continue
+			}
+			req.Hostname, req.Namespace, req.Type = parseProviderSourceAddr(source)
+
+		case "version":
+			var constraintStr string
+			if err := evaluateStringExpr(pair.Value, &constraintStr); err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid version argument",
+					Detail:   err.Error(),
+					Subject:  pair.Value.Range().Ptr(),
+				})
+				continue
+			}
+			constraints, err := version.NewConstraint(constraintStr)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid version constraint",
+					Detail:   err.Error(),
+					Subject:  pair.Value.Range().Ptr(),
+				})
+				continue
+			}
+			req.Constraints = constraints
+			req.ConstraintsExpr = pair.Value
+
+		case "configuration_aliases":
+			aliases, aliasDiags := hcl.ExprList(pair.Value)
+			if aliasDiags.HasErrors() {
+				diags = append(diags, aliasDiags...)
+				continue
+			}
+			for _, aliasExpr := range aliases {
+				traversal, travDiags := hcl.AbsTraversalForExpr(aliasExpr)
+				if travDiags.HasErrors() {
+					diags = append(diags, travDiags...)
+					continue
+				}
+				req.ConfigurationAliases = append(req.ConfigurationAliases, traversal)
+			}
+		}
+	}
+
+	return req, diags
+}
+
+func pairKeyString(keyExpr hcl.Expression) (string, hcl.Diagnostics) {
+	var key string
+	if err := evaluateStringExpr(keyExpr, &key); err != nil {
+		return "", hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid required_providers key",
+			Detail:   err.Error(),
+			Subject:  keyExpr.Range().Ptr(),
+		}}
+	}
+	return key, nil
+}
+
+// evaluateStringExpr evaluates a static HCL expression as a string without
+// needing a Runner, since required_providers entries never reference
+// module variables.
+func evaluateStringExpr(expr hcl.Expression, target *string) error {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return diags
+	}
+	if val.IsNull() {
+		return fmt.Errorf("value is null")
+	}
+	val, err := convert.Convert(val, cty.String)
+	if err != nil {
+		return err
+	}
+	*target = val.AsString()
+	return nil
+}
+
+// parseProviderSourceAddr splits a provider source address such as
+// "registry.example.com/org/name" or the shorthand "hashicorp/aws" into its
+// hostname, namespace, and type parts.
+func parseProviderSourceAddr(source string) (hostname, namespace, typeName string) {
+	parts := strings.Split(source, "/")
+	switch len(parts) {
+	case 1:
+		return defaultProviderRegistryHost, "hashicorp", parts[0]
+	case 2:
+		return defaultProviderRegistryHost, parts[0], parts[1]
+	default:
+		return parts[0], parts[len(parts)-2], parts[len(parts)-1]
+	}
+}
@@ -0,0 +1,73 @@
+package terraform
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+
+	"github.com/terraform-linters/tflint-ruleset-terraform/terraform/blocktoattr"
+	"github.com/terraform-linters/tflint-ruleset-terraform/terraform/configschema"
+)
+
+// resourceBlocksOfType returns every `resource` block of resourceType,
+// decoded with the given body schema for its contents.
+func (r *Runner) resourceBlocksOfType(resourceType string, bodySchema *hclext.BodySchema) ([]*hclext.Block, hcl.Diagnostics) {
+	schema := &hclext.BodySchema{
+		Blocks: []hclext.BlockSchema{
+			{Type: "resource", LabelNames: []string{"type", "name"}, Body: bodySchema},
+		},
+	}
+
+	content, diags := r.getModuleContentDiags(schema, &tflint.GetModuleContentOption{})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var blocks []*hclext.Block
+	for _, block := range content.Blocks {
+		if block.Labels[0] == resourceType {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, diags
+}
+
+// WalkExpandedResourceAttributes calls walker once per resourceType block
+// that declares attrName, with the attribute rewritten from nested-block
+// syntax into an equivalent attribute expression whenever the configuration
+// wrote it that way. schema describes the object type of each element of
+// attrName, mirroring the reconciliation Terraform core's blocktoattr
+// package performs before variable/traversal extraction, so rules that
+// inspect attrName don't miss references written in block form.
+func (r *Runner) WalkExpandedResourceAttributes(resourceType, attrName string, schema *configschema.Block, walker func(*hclext.Attribute) hcl.Diagnostics) hcl.Diagnostics {
+	blocks, diags := r.resourceBlocksOfType(resourceType, blocktoattr.BodySchemaForAttr(attrName, schema))
+	if diags.HasErrors() {
+		return diags
+	}
+
+	for _, block := range blocks {
+		attr := blocktoattr.ExpandAttr(block.Body, attrName, schema)
+		if attr == nil {
+			continue
+		}
+		diags = append(diags, walker(attr)...)
+	}
+
+	return diags
+}
+
+// ExpandedVariables returns every variable traversal referenced by attrName
+// across all resourceType blocks, after the same block-to-attribute
+// expansion WalkExpandedResourceAttributes performs. This lets rules find
+// variable references regardless of whether the configuration used
+// attribute or nested block syntax.
+func (r *Runner) ExpandedVariables(resourceType, attrName string, schema *configschema.Block) ([]hcl.Traversal, hcl.Diagnostics) {
+	var traversals []hcl.Traversal
+
+	diags := r.WalkExpandedResourceAttributes(resourceType, attrName, schema, func(attr *hclext.Attribute) hcl.Diagnostics {
+		traversals = append(traversals, attr.Expr.Variables()...)
+		return nil
+	})
+
+	return traversals, diags
+}
@@ -0,0 +1,47 @@
+// Package terraform provides a thin wrapper around tflint.Runner that
+// understands Terraform language constructs (module calls, locals, provider
+// references, and so on) so that individual rules don't need to re-implement
+// HCL schema decoding themselves.
+package terraform
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+// Runner wraps tflint.Runner and adds helpers for extracting
+// Terraform-specific language constructs from the module under lint.
+type Runner struct {
+	tflint.Runner
+
+	// moduleMeta caches the result of GetModuleMeta, which aggregates the
+	// data several other Runner methods return and is expensive enough to
+	// compute once per run.
+	moduleMeta *ModuleMeta
+}
+
+// NewRunner returns a new Runner wrapping the given tflint.Runner.
+func NewRunner(runner tflint.Runner) *Runner {
+	return &Runner{Runner: runner}
+}
+
+// getModuleContentDiags wraps Runner.GetModuleContent, converting the error
+// it returns into hcl.Diagnostics so every helper in this package can keep
+// propagating diagnostics uniformly.
+func (r *Runner) getModuleContentDiags(schema *hclext.BodySchema, option *tflint.GetModuleContentOption) (*hclext.BodyContent, hcl.Diagnostics) {
+	body, err := r.GetModuleContent(schema, option)
+	if err != nil {
+		if diags, ok := err.(hcl.Diagnostics); ok {
+			return body, diags
+		}
+		return body, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to get module content",
+				Detail:   err.Error(),
+			},
+		}
+	}
+	return body, nil
+}
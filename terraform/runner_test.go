@@ -11,6 +11,8 @@ import (
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	"github.com/terraform-linters/tflint-plugin-sdk/helper"
 	"github.com/zclconf/go-cty/cty"
+
+	"github.com/terraform-linters/tflint-ruleset-terraform/terraform/configschema"
 )
 
 func TestGetModuleCalls(t *testing.T) {
@@ -56,6 +58,7 @@ module "server" {
 							End:      hcl.Pos{Line: 3, Column: 9},
 						},
 					},
+					ResolvedSource: ModuleSourceLocal{Path: "./server"},
 				},
 			},
 		},
@@ -104,6 +107,12 @@ module "vpc" {
 							End:      hcl.Pos{Line: 4, Column: 10},
 						},
 					},
+					ResolvedSource: ModuleSourceRegistry{
+						Host:         "registry.terraform.io",
+						Namespace:    "terraform-aws-modules",
+						Name:         "vpc",
+						TargetSystem: "aws",
+					},
 				},
 			},
 		},
@@ -132,6 +141,108 @@ module "vpc" {
 	}
 }
 
+func TestGetModuleCalls_reservedMetaArguments(t *testing.T) {
+	runner := NewRunner(helper.TestRunner(t, map[string]string{
+		"main.tf": `
+module "server" {
+  source     = "./server"
+  count      = 3
+  for_each   = toset(["a"])
+  depends_on = [aws_instance.foo]
+  providers  = {
+    aws = aws.west
+  }
+}`,
+	}))
+
+	got, diags := runner.GetModuleCalls()
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected a single module call, got %d", len(got))
+	}
+
+	call := got[0]
+	if call.CountExpr == nil {
+		t.Error("expected CountExpr to be populated")
+	}
+	if call.ForEachExpr == nil {
+		t.Error("expected ForEachExpr to be populated")
+	}
+	if call.DependsOn == nil {
+		t.Error("expected DependsOn to be populated")
+	}
+	if call.Providers == nil {
+		t.Error("expected Providers to be populated")
+	}
+}
+
+func TestGetModuleCallReservedArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     bool
+		content  string
+		wantErrs int
+	}{
+		{
+			name: "no reserved args",
+			content: `
+module "server" {
+  source = "./server"
+}`,
+			wantErrs: 0,
+		},
+		{
+			name: "lifecycle is reserved",
+			content: `
+module "server" {
+  source    = "./server"
+  lifecycle = true
+}`,
+			wantErrs: 1,
+		},
+		{
+			name: "singular provider is reserved",
+			content: `
+module "server" {
+  source   = "./server"
+  provider = "aws"
+}`,
+			wantErrs: 1,
+		},
+		{
+			name: "lifecycle is reserved in JSON",
+			json: true,
+			content: `
+{
+  "module": {
+    "server": {
+      "source": "./server",
+      "lifecycle": true
+    }
+  }
+}`,
+			wantErrs: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := "main.tf"
+			if test.json {
+				filename += ".json"
+			}
+			runner := NewRunner(helper.TestRunner(t, map[string]string{filename: test.content}))
+
+			diags := runner.GetModuleCallReservedArgs()
+			if len(diags) != test.wantErrs {
+				t.Errorf("got %d diagnostics, want %d: %s", len(diags), test.wantErrs, diags)
+			}
+		})
+	}
+}
+
 func TestGetLocals(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -283,7 +394,7 @@ output "foo" {
   }
 }`,
 			want: map[string]*ProviderRef{
-				"time": {Name: "time", DefRange: hcl.Range{Filename: "main.tf.json", Start: hcl.Pos{Line: 3, Column: 15}, End: hcl.Pos{Line: 3, Column: 68}}},
+				"time": {Name: "time", DefRange: hcl.Range{Filename: "main.tf.json", Start: hcl.Pos{Line: 5, Column: 19}, End: hcl.Pos{Line: 5, Column: 72}}},
 			},
 		},
 	}
@@ -310,3 +421,522 @@ output "foo" {
 		})
 	}
 }
+
+func TestGetProviderRequirements(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  map[string]*ProviderRequirement
+	}{
+		{
+			name: "object syntax",
+			files: map[string]string{
+				"main.tf": `
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}`,
+			},
+			want: map[string]*ProviderRequirement{
+				"aws": {
+					LocalName:   "aws",
+					Hostname:    "registry.terraform.io",
+					Namespace:   "hashicorp",
+					Type:        "aws",
+					Constraints: version.MustConstraints(version.NewConstraint("~> 5.0")),
+				},
+			},
+		},
+		{
+			name: "legacy shorthand",
+			files: map[string]string{
+				"main.tf": `
+terraform {
+  required_providers {
+    google = "~> 4.0"
+  }
+}`,
+			},
+			want: map[string]*ProviderRequirement{
+				"google": {
+					LocalName:   "google",
+					Hostname:    "registry.terraform.io",
+					Namespace:   "hashicorp",
+					Type:        "google",
+					Constraints: version.MustConstraints(version.NewConstraint("~> 4.0")),
+				},
+			},
+		},
+		{
+			name: "merges multiple terraform blocks across files",
+			files: map[string]string{
+				"main.tf": `
+terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}`,
+				"versions.tf": `
+terraform {
+  required_providers {
+    random = {
+      source  = "hashicorp/random"
+      version = ">= 3.0"
+    }
+  }
+}`,
+			},
+			want: map[string]*ProviderRequirement{
+				"aws": {
+					LocalName: "aws",
+					Hostname:  "registry.terraform.io",
+					Namespace: "hashicorp",
+					Type:      "aws",
+				},
+				"random": {
+					LocalName:   "random",
+					Hostname:    "registry.terraform.io",
+					Namespace:   "hashicorp",
+					Type:        "random",
+					Constraints: version.MustConstraints(version.NewConstraint(">= 3.0")),
+				},
+			},
+		},
+		{
+			name: "json syntax",
+			files: map[string]string{
+				"main.tf.json": `
+{
+  "terraform": {
+    "required_providers": {
+      "aws": {
+        "source": "hashicorp/aws",
+        "version": "~> 5.0"
+      }
+    }
+  }
+}`,
+			},
+			want: map[string]*ProviderRequirement{
+				"aws": {
+					LocalName:   "aws",
+					Hostname:    "registry.terraform.io",
+					Namespace:   "hashicorp",
+					Type:        "aws",
+					Constraints: version.MustConstraints(version.NewConstraint("~> 5.0")),
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := NewRunner(helper.TestRunner(t, test.files))
+
+			got, diags := runner.GetProviderRequirements()
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			opts := []cmp.Option{
+				cmpopts.IgnoreFields(hcl.Range{}, "Filename"),
+				cmpopts.IgnoreFields(hcl.Pos{}, "Byte"),
+				cmpopts.IgnoreFields(ProviderRequirement{}, "DefRange", "ConstraintsExpr"),
+				cmpopts.IgnoreUnexported(version.Constraint{}),
+			}
+			if diff := cmp.Diff(got, test.want, opts...); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGetProviderRequirements_duplicateAndReservedNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "duplicate local name across blocks",
+			content: `
+terraform {
+  required_providers {
+    aws = { source = "hashicorp/aws" }
+  }
+}
+terraform {
+  required_providers {
+    aws = { source = "hashicorp/aws", version = "~> 5.0" }
+  }
+}`,
+		},
+		{
+			name: "reserved local name",
+			content: `
+terraform {
+  required_providers {
+    terraform = { source = "hashicorp/terraform" }
+  }
+}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := NewRunner(helper.TestRunner(t, map[string]string{"main.tf": test.content}))
+
+			_, diags := runner.GetProviderRequirements()
+			if !diags.HasErrors() {
+				t.Fatal("expected a diagnostic, got none")
+			}
+		})
+	}
+}
+
+func TestGetModuleMeta(t *testing.T) {
+	runner := NewRunner(helper.TestRunner(t, map[string]string{
+		"main.tf": `
+terraform {
+  required_version = ">= 1.5.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+variable "name" {}
+
+output "arn" {
+  value = aws_instance.this.arn
+}
+
+module "vpc" {
+  source = "./vpc"
+}`,
+		"extra.tf": `
+variable "region" {}`,
+	}))
+
+	meta, diags := runner.GetModuleMeta()
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if want := version.MustConstraints(version.NewConstraint(">= 1.5.0")); meta.CoreVersionConstraints.String() != want.String() {
+		t.Errorf("CoreVersionConstraints = %s, want %s", meta.CoreVersionConstraints, want)
+	}
+	if _, exists := meta.ProviderRequirements["aws"]; !exists {
+		t.Error("expected ProviderRequirements to contain \"aws\"")
+	}
+	if _, exists := meta.Variables["name"]; !exists {
+		t.Error("expected Variables to contain \"name\"")
+	}
+	if _, exists := meta.Variables["region"]; !exists {
+		t.Error("expected Variables to contain \"region\"")
+	}
+	if _, exists := meta.Outputs["arn"]; !exists {
+		t.Error("expected Outputs to contain \"arn\"")
+	}
+	if len(meta.ModuleCalls) != 1 || meta.ModuleCalls[0].Name != "vpc" {
+		t.Errorf("expected a single \"vpc\" module call, got %#v", meta.ModuleCalls)
+	}
+	if want := []string{"extra.tf", "main.tf"}; !cmp.Equal(meta.Files, want) {
+		t.Errorf("Files = %v, want %v", meta.Files, want)
+	}
+	if meta.PrimaryFile != "main.tf" {
+		t.Errorf("PrimaryFile = %q, want \"main.tf\"", meta.PrimaryFile)
+	}
+
+	// A second call should reuse the cached result.
+	cached, diags := runner.GetModuleMeta()
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if cached != meta {
+		t.Error("expected GetModuleMeta to return the cached value on a second call")
+	}
+}
+
+func TestWalkExpandedResourceAttributes(t *testing.T) {
+	// Schema for a "setting" attribute of type list(object({ name = string, nested = list(object({ key = string })) })).
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"name":   {Type: cty.String, Optional: true},
+			"nested": {Type: cty.List(cty.Object(map[string]cty.Type{"key": cty.String})), Optional: true},
+		},
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"nested": {
+				Nesting: configschema.NestingList,
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"key": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		json      bool
+		content   string
+		wantCount int
+	}{
+		{
+			name: "attribute syntax",
+			content: `
+resource "foo_bar" "baz" {
+  setting = [{ name = "a", nested = [] }]
+}`,
+			wantCount: 1,
+		},
+		{
+			name: "block syntax",
+			content: `
+resource "foo_bar" "baz" {
+  setting {
+    name = "a"
+  }
+}`,
+			wantCount: 1,
+		},
+		{
+			name: "nested block syntax",
+			content: `
+resource "foo_bar" "baz" {
+  setting {
+    name = "a"
+    nested {
+      key = "k"
+    }
+  }
+}`,
+			wantCount: 1,
+		},
+		{
+			name: "json attribute syntax",
+			json: true,
+			content: `
+{
+  "resource": {
+    "foo_bar": {
+      "baz": {
+        "setting": [{ "name": "a" }]
+      }
+    }
+  }
+}`,
+			wantCount: 1,
+		},
+		{
+			name: "absent",
+			content: `
+resource "foo_bar" "baz" {}`,
+			wantCount: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := "main.tf"
+			if test.json {
+				filename += ".json"
+			}
+			runner := NewRunner(helper.TestRunner(t, map[string]string{filename: test.content}))
+
+			var got int
+			diags := runner.WalkExpandedResourceAttributes("foo_bar", "setting", schema, func(attr *hclext.Attribute) hcl.Diagnostics {
+				got++
+				if attr.Expr == nil {
+					t.Error("expected a non-nil expression")
+				}
+				return nil
+			})
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			if got != test.wantCount {
+				t.Errorf("walker called %d times, want %d", got, test.wantCount)
+			}
+		})
+	}
+}
+
+func TestExpandedVariables(t *testing.T) {
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"name": {Type: cty.String, Optional: true},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "attribute syntax",
+			content: `
+resource "foo_bar" "baz" {
+  setting = [{ name = var.example }]
+}`,
+		},
+		{
+			name: "block syntax",
+			content: `
+resource "foo_bar" "baz" {
+  setting {
+    name = var.example
+  }
+}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := NewRunner(helper.TestRunner(t, map[string]string{"main.tf": test.content}))
+
+			traversals, diags := runner.ExpandedVariables("foo_bar", "setting", schema)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			if len(traversals) != 1 {
+				t.Fatalf("expected a single traversal, got %d", len(traversals))
+			}
+			if root := traversals[0].RootName(); root != "var" {
+				t.Errorf("RootName() = %q, want \"var\"", root)
+			}
+		})
+	}
+}
+
+func TestGetModuleCalls_resolvedSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   ModuleSource
+	}{
+		{
+			name:   "local",
+			source: "../shared/vpc",
+			want:   ModuleSourceLocal{Path: "../shared/vpc"},
+		},
+		{
+			name:   "registry with host",
+			source: "app.terraform.io/example-corp/vpc/aws",
+			want: ModuleSourceRegistry{
+				Host:         "app.terraform.io",
+				Namespace:    "example-corp",
+				Name:         "vpc",
+				TargetSystem: "aws",
+			},
+		},
+		{
+			name:   "registry with subdir",
+			source: "terraform-aws-modules/vpc/aws//submodule",
+			want: ModuleSourceRegistry{
+				Host:         "registry.terraform.io",
+				Namespace:    "terraform-aws-modules",
+				Name:         "vpc",
+				TargetSystem: "aws",
+				Subdir:       "submodule",
+			},
+		},
+		{
+			name:   "forced git detector",
+			source: "git::https://example.com/vpc.git",
+			want:   ModuleSourceRemote{Detector: "git", Raw: "https://example.com/vpc.git"},
+		},
+		{
+			name:   "github shorthand",
+			source: "github.com/hashicorp/example",
+			want:   ModuleSourceRemote{Detector: "github", Raw: "github.com/hashicorp/example"},
+		},
+		{
+			name:   "plain https url",
+			source: "https://example.com/vpc.zip",
+			want:   ModuleSourceRemote{Detector: "https", Raw: "https://example.com/vpc.zip"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := NewRunner(helper.TestRunner(t, map[string]string{
+				"main.tf": `
+module "this" {
+  source = "` + test.source + `"
+}`,
+			}))
+
+			calls, diags := runner.GetModuleCalls()
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			if len(calls) != 1 {
+				t.Fatalf("expected a single module call, got %d", len(calls))
+			}
+
+			if diff := cmp.Diff(calls[0].ResolvedSource, test.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGetModuleCalls_sourceDiagnostics(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "version on local source",
+			content: `
+module "this" {
+  source  = "./server"
+  version = "1.0.0"
+}`,
+		},
+		{
+			name: "version on remote source",
+			content: `
+module "this" {
+  source  = "git::https://example.com/vpc.git"
+  version = "1.0.0"
+}`,
+		},
+		{
+			name: "git ref combined with version",
+			content: `
+module "this" {
+  source  = "git::https://example.com/vpc.git?ref=v1.0.0"
+  version = "1.0.0"
+}`,
+		},
+		{
+			name: "local source escapes repository",
+			content: `
+module "this" {
+  source = "../../../outside"
+}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := NewRunner(helper.TestRunner(t, map[string]string{"main.tf": test.content}))
+
+			_, diags := runner.GetModuleCalls()
+			if !diags.HasErrors() {
+				t.Fatal("expected a diagnostic, got none")
+			}
+		})
+	}
+}